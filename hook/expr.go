@@ -0,0 +1,455 @@
+package hook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExprRule evaluates a single boolean expression against the request's
+// headers, query, payload and body, e.g.
+//
+//	payload.ref == "refs/heads/main" && payload.pusher.name in ["alice", "bob"]
+//
+// The expression language is intentionally tiny and has no access to
+// arbitrary functions or I/O: literals, comparisons, boolean logic,
+// membership (`in`) and dotted attribute access (equivalent to
+// GetParameter) are all it supports.
+type ExprRule struct {
+	Expression string `json:"expression,omitempty" yaml:"expression,omitempty"`
+}
+
+// Evaluate ExprRule parses Expression and evaluates it against ctx, requiring
+// the result to be a boolean
+func (r ExprRule) Evaluate(ctx *EvaluateContext) (bool, error) {
+	var headers, query, payload map[string]interface{}
+	if ctx.Headers != nil {
+		headers = *ctx.Headers
+	}
+	if ctx.Query != nil {
+		query = *ctx.Query
+	}
+	if ctx.Payload != nil {
+		payload = *ctx.Payload
+	}
+
+	var body string
+	if ctx.Body != nil {
+		body = string(*ctx.Body)
+	}
+
+	tokens, err := tokenizeExpr(r.Expression)
+	if err != nil {
+		return false, &ExprError{r.Expression, err}
+	}
+
+	p := &exprParser{
+		tokens: tokens,
+		vars: map[string]interface{}{
+			"headers": headers,
+			"query":   query,
+			"payload": payload,
+			"body":    body,
+		},
+	}
+
+	value, err := p.parseOr()
+	if err != nil {
+		return false, &ExprError{r.Expression, err}
+	}
+	if !p.atEnd() {
+		return false, &ExprError{r.Expression, fmt.Errorf("unexpected token %q", p.peek().val)}
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return false, &ExprError{r.Expression, fmt.Errorf("expression did not evaluate to a boolean, got %v", value)}
+	}
+
+	return b, nil
+}
+
+// ExprError describes a failure to parse or evaluate an ExprRule expression.
+type ExprError struct {
+	Expression string
+	Err        error
+}
+
+func (e *ExprError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("invalid expression %q: %s", e.Expression, e.Err)
+}
+
+type exprTokenKind int
+
+const (
+	exprTokIdent exprTokenKind = iota
+	exprTokString
+	exprTokNumber
+	exprTokOp
+	exprTokLParen
+	exprTokRParen
+	exprTokLBracket
+	exprTokRBracket
+	exprTokComma
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	val  string
+}
+
+// tokenizeExpr breaks expr into the tokens understood by exprParser
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+
+	i, n := 0, len(expr)
+	for i < n {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, exprToken{exprTokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, exprToken{exprTokRParen, ")"})
+			i++
+		case c == '[':
+			tokens = append(tokens, exprToken{exprTokLBracket, "["})
+			i++
+		case c == ']':
+			tokens = append(tokens, exprToken{exprTokRBracket, "]"})
+			i++
+		case c == ',':
+			tokens = append(tokens, exprToken{exprTokComma, ","})
+			i++
+
+		case c == '"':
+			j := i + 1
+			for j < n && expr[j] != '"' {
+				j++
+			}
+			if j >= n {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, exprToken{exprTokString, expr[i+1 : j]})
+			i = j + 1
+
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"),
+			strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="):
+			tokens = append(tokens, exprToken{exprTokOp, expr[i : i+2]})
+			i += 2
+
+		case c == '!' || c == '<' || c == '>' || c == '-':
+			tokens = append(tokens, exprToken{exprTokOp, string(c)})
+			i++
+
+		case c >= '0' && c <= '9':
+			j := i
+			for j < n && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, exprToken{exprTokNumber, expr[i:j]})
+			i = j
+
+		case isExprIdentByte(c):
+			j := i
+			for j < n && isExprIdentByte(expr[j]) {
+				j++
+			}
+			tokens = append(tokens, exprToken{exprTokIdent, expr[i:j]})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isExprIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// exprParser is a small recursive-descent parser and evaluator combined:
+// each parseX method both consumes tokens and returns the evaluated value,
+// since the language has no side effects and every subexpression is
+// evaluated exactly once.
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	vars   map[string]interface{}
+}
+
+func (p *exprParser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *exprParser) peek() exprToken {
+	if p.atEnd() {
+		return exprToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.atEnd() && p.peek().kind == exprTokOp && p.peek().val == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = toBool(left) || toBool(right)
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.atEnd() && p.peek().kind == exprTokOp && p.peek().val == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = toBool(left) && toBool(right)
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (interface{}, error) {
+	if !p.atEnd() && p.peek().kind == exprTokOp && p.peek().val == "!" {
+		p.next()
+		v, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return !toBool(v), nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (interface{}, error) {
+	left, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.atEnd() {
+		return left, nil
+	}
+
+	tok := p.peek()
+
+	if tok.kind == exprTokIdent && tok.val == "in" {
+		p.next()
+		list, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		return exprIn(left, list), nil
+	}
+
+	if tok.kind == exprTokOp && (tok.val == "==" || tok.val == "!=" || tok.val == "<" || tok.val == "<=" || tok.val == ">" || tok.val == ">=") {
+		p.next()
+		right, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		return exprCompare(tok.val, left, right)
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseAtom() (interface{}, error) {
+	if p.atEnd() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	tok := p.next()
+
+	switch tok.kind {
+	case exprTokOp:
+		if tok.val != "-" {
+			return nil, fmt.Errorf("unexpected token %q", tok.val)
+		}
+
+		v, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("unary '-' requires a numeric operand, got %v", v)
+		}
+		return -f, nil
+
+	case exprTokLParen:
+		v, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.atEnd() || p.peek().kind != exprTokRParen {
+			return nil, fmt.Errorf("expected closing ')'")
+		}
+		p.next()
+		return v, nil
+
+	case exprTokLBracket:
+		var items []interface{}
+		if !p.atEnd() && p.peek().kind != exprTokRBracket {
+			for {
+				item, err := p.parseAtom()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+
+				if !p.atEnd() && p.peek().kind == exprTokComma {
+					p.next()
+					continue
+				}
+				break
+			}
+		}
+		if p.atEnd() || p.peek().kind != exprTokRBracket {
+			return nil, fmt.Errorf("expected closing ']'")
+		}
+		p.next()
+		return items, nil
+
+	case exprTokString:
+		return tok.val, nil
+
+	case exprTokNumber:
+		f, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.val)
+		}
+		return f, nil
+
+	case exprTokIdent:
+		switch tok.val {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return p.resolveIdent(tok.val), nil
+	}
+
+	return nil, fmt.Errorf("unexpected token %q", tok.val)
+}
+
+// resolveIdent resolves a dotted identifier path (e.g. "payload.ref")
+// against the rule's variables, reusing GetParameter for everything past
+// the root name
+func (p *exprParser) resolveIdent(path string) interface{} {
+	root := path
+	rest := ""
+	if idx := strings.Index(path, "."); idx >= 0 {
+		root, rest = path[:idx], path[idx+1:]
+	}
+
+	value, ok := p.vars[root]
+	if !ok {
+		return nil
+	}
+	if rest == "" {
+		return value
+	}
+
+	resolved, ok := GetParameter(rest, value)
+	if !ok {
+		return nil
+	}
+	return resolved
+}
+
+func toBool(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func exprIn(needle, haystack interface{}) bool {
+	items, ok := haystack.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, item := range items {
+		if exprEqual(needle, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func exprEqual(a, b interface{}) bool {
+	if af, aok := toFloat(a); aok {
+		if bf, bok := toFloat(b); bok {
+			return af == bf
+		}
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func exprCompare(op string, a, b interface{}) (interface{}, error) {
+	if op == "==" {
+		return exprEqual(a, b), nil
+	}
+	if op == "!=" {
+		return !exprEqual(a, b), nil
+	}
+
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if !aok || !bok {
+		return nil, fmt.Errorf("operator %q requires numeric operands", op)
+	}
+
+	switch op {
+	case "<":
+		return af < bf, nil
+	case "<=":
+		return af <= bf, nil
+	case ">":
+		return af > bf, nil
+	case ">=":
+		return af >= bf, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
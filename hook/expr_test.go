@@ -0,0 +1,122 @@
+package hook
+
+import "testing"
+
+func evaluateExpr(t *testing.T, expression string, ctx *EvaluateContext) bool {
+	t.Helper()
+
+	result, err := (ExprRule{Expression: expression}).Evaluate(ctx)
+	if err != nil {
+		t.Fatalf("Evaluate(%q) returned error: %s", expression, err)
+	}
+	return result
+}
+
+func TestExprRuleLiteralsAndComparisons(t *testing.T) {
+	ctx := &EvaluateContext{}
+
+	cases := map[string]bool{
+		`"a" == "a"`:     true,
+		`"a" == "b"`:     false,
+		`"a" != "b"`:     true,
+		`1 == 1`:         true,
+		`1 == 1.0`:       true,
+		`1 < 2`:          true,
+		`2 <= 2`:         true,
+		`3 > 2`:          true,
+		`2 >= 3`:         false,
+		`true == true`:   true,
+		`false != true`:  true,
+	}
+
+	for expr, want := range cases {
+		if got := evaluateExpr(t, expr, ctx); got != want {
+			t.Errorf("Evaluate(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestExprRuleNegativeNumbers(t *testing.T) {
+	payload := map[string]interface{}{"exit_code": -1.0}
+	ctx := &EvaluateContext{Payload: &payload}
+
+	if !evaluateExpr(t, "payload.exit_code == -1", ctx) {
+		t.Errorf("expected payload.exit_code == -1 to be true")
+	}
+	if evaluateExpr(t, "payload.exit_code == -2", ctx) {
+		t.Errorf("expected payload.exit_code == -2 to be false")
+	}
+	if !evaluateExpr(t, "-1 < 0", ctx) {
+		t.Errorf("expected -1 < 0 to be true")
+	}
+}
+
+func TestExprRuleBooleanLogic(t *testing.T) {
+	ctx := &EvaluateContext{}
+
+	if !evaluateExpr(t, "true && true", ctx) {
+		t.Errorf("expected true && true to be true")
+	}
+	if evaluateExpr(t, "true && false", ctx) {
+		t.Errorf("expected true && false to be false")
+	}
+	if !evaluateExpr(t, "false || true", ctx) {
+		t.Errorf("expected false || true to be true")
+	}
+	if !evaluateExpr(t, "!false", ctx) {
+		t.Errorf("expected !false to be true")
+	}
+	if !evaluateExpr(t, "!(1 == 2) && (2 == 2)", ctx) {
+		t.Errorf("expected !(1 == 2) && (2 == 2) to be true")
+	}
+}
+
+func TestExprRuleMembership(t *testing.T) {
+	payload := map[string]interface{}{
+		"pusher": map[string]interface{}{"name": "alice"},
+	}
+	ctx := &EvaluateContext{Payload: &payload}
+
+	if !evaluateExpr(t, `payload.pusher.name in ["alice", "bob"]`, ctx) {
+		t.Errorf(`expected payload.pusher.name in ["alice", "bob"] to be true`)
+	}
+	if evaluateExpr(t, `payload.pusher.name in ["carol", "bob"]`, ctx) {
+		t.Errorf(`expected payload.pusher.name in ["carol", "bob"] to be false`)
+	}
+}
+
+func TestExprRuleDottedAttributeAccess(t *testing.T) {
+	headers := map[string]interface{}{"Event": "push"}
+	query := map[string]interface{}{"token": "s3cr3t"}
+	payload := map[string]interface{}{
+		"ref":    "refs/heads/main",
+		"pusher": map[string]interface{}{"name": "alice"},
+	}
+	body := []byte(`{"ref":"refs/heads/main"}`)
+
+	ctx := &EvaluateContext{
+		Headers: &headers,
+		Query:   &query,
+		Payload: &payload,
+		Body:    &body,
+	}
+
+	expr := `payload.ref == "refs/heads/main" && payload.pusher.name in ["alice", "bob"]`
+	if !evaluateExpr(t, expr, ctx) {
+		t.Errorf("expected %q to be true", expr)
+	}
+
+	if !evaluateExpr(t, `headers.Event == "push"`, ctx) {
+		t.Errorf(`expected headers.Event == "push" to be true`)
+	}
+	if !evaluateExpr(t, `query.token == "s3cr3t"`, ctx) {
+		t.Errorf(`expected query.token == "s3cr3t" to be true`)
+	}
+}
+
+func TestExprRuleNonBooleanResultIsError(t *testing.T) {
+	_, err := (ExprRule{Expression: `"just a string"`}).Evaluate(&EvaluateContext{})
+	if err == nil {
+		t.Errorf("expected a non-boolean expression result to return an error")
+	}
+}
@@ -8,8 +8,8 @@ import (
 
 // Header is a structure containing header name and it's value
 type Header struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
 }
 
 // ResponseHeaders is a slice of Header objects
@@ -1,24 +1,100 @@
 package hook
 
 import (
-	"io/ioutil"
+	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
 	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
 )
 
 
 // Hook type is a structure containing details for a single hook
 type Hook struct {
-	ID                       string          `json:"id,omitempty"`
-	ExecuteCommand           string          `json:"execute-command,omitempty"`
-	CommandWorkingDirectory  string          `json:"command-working-directory,omitempty"`
-	ResponseMessage          string          `json:"response-message,omitempty"`
-	ResponseHeaders          ResponseHeaders `json:"response-headers,omitempty"`
-	CaptureCommandOutput     bool            `json:"include-command-output-in-response,omitempty"`
-	PassEnvironmentToCommand []Argument      `json:"pass-environment-to-command,omitempty"`
-	PassArgumentsToCommand   []Argument      `json:"pass-arguments-to-command,omitempty"`
-	JSONStringParameters     []Argument      `json:"parse-parameters-as-json,omitempty"`
-	TriggerRule              *Rules          `json:"trigger-rule,omitempty"`
+	ID                        string          `json:"id,omitempty" yaml:"id,omitempty"`
+	ExecuteCommand            string          `json:"execute-command,omitempty" yaml:"execute-command,omitempty"`
+	CommandWorkingDirectory   string          `json:"command-working-directory,omitempty" yaml:"command-working-directory,omitempty"`
+	ResponseMessage           string          `json:"response-message,omitempty" yaml:"response-message,omitempty"`
+	ResponseHeaders           ResponseHeaders `json:"response-headers,omitempty" yaml:"response-headers,omitempty"`
+	CaptureCommandOutput      bool            `json:"include-command-output-in-response,omitempty" yaml:"include-command-output-in-response,omitempty"`
+	PassEnvironmentToCommand  []Argument      `json:"pass-environment-to-command,omitempty" yaml:"pass-environment-to-command,omitempty"`
+	PassArgumentsToCommand    []Argument      `json:"pass-arguments-to-command,omitempty" yaml:"pass-arguments-to-command,omitempty"`
+	JSONStringParameters      []Argument      `json:"parse-parameters-as-json,omitempty" yaml:"parse-parameters-as-json,omitempty"`
+	TriggerRule               *Rules          `json:"trigger-rule,omitempty" yaml:"trigger-rule,omitempty"`
+	ResponseTemplate          string          `json:"response-template,omitempty" yaml:"response-template,omitempty"`
+	ResponseContentType       string          `json:"response-content-type,omitempty" yaml:"response-content-type,omitempty"`
+	ResponseStatusCodeOnError int             `json:"response-status-code-on-error,omitempty" yaml:"response-status-code-on-error,omitempty"`
+}
+
+// DefaultResponseContentType is used for a Hook's templated or plain
+// ResponseMessage body whenever ResponseContentType is left unset, and for
+// every error response of RenderResponse; the JSON-encoded
+// CommandStatusResponse is always sent as application/json
+const DefaultResponseContentType = "text/plain; charset=utf-8"
+
+// TemplateContext is made available to a Hook's ResponseTemplate so it can
+// build a provider-specific reply body (e.g. a Slack-style JSON block, or a
+// GitHub check-run status) instead of the fixed CommandStatusResponse shape.
+type TemplateContext struct {
+	Headers  map[string]interface{}
+	Query    map[string]interface{}
+	Payload  map[string]interface{}
+	Output   string
+	Error    string
+	ExitCode int
+}
+
+// RenderResponse builds the HTTP status code, content type and body that
+// should be sent back for a completed hook invocation. If ResponseTemplate
+// is set, it is executed against ctx; otherwise the response falls back to
+// a JSON-encoded CommandStatusResponse when CaptureCommandOutput is set, or
+// plain ResponseMessage text, matching the pre-template behaviour.
+func (h *Hook) RenderResponse(ctx TemplateContext) (statusCode int, contentType string, body string, err error) {
+	statusCode = http.StatusOK
+	if ctx.Error != "" && h.ResponseStatusCodeOnError != 0 {
+		statusCode = h.ResponseStatusCodeOnError
+	}
+
+	contentType = h.ResponseContentType
+	if contentType == "" {
+		contentType = DefaultResponseContentType
+	}
+
+	if h.ResponseTemplate != "" {
+		tmpl, err := template.New(h.ID).Parse(h.ResponseTemplate)
+		if err != nil {
+			return http.StatusInternalServerError, DefaultResponseContentType, "", err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ctx); err != nil {
+			return http.StatusInternalServerError, DefaultResponseContentType, "", err
+		}
+
+		return statusCode, contentType, buf.String(), nil
+	}
+
+	if h.CaptureCommandOutput {
+		resp := CommandStatusResponse{
+			ResponseMessage: h.ResponseMessage,
+			Output:          ctx.Output,
+			Error:           ctx.Error,
+			ExitCode:        ctx.ExitCode,
+		}
+
+		b, err := json.Marshal(resp)
+		if err != nil {
+			return http.StatusInternalServerError, DefaultResponseContentType, "", err
+		}
+
+		return statusCode, "application/json", string(b), nil
+	}
+
+	return statusCode, contentType, h.ResponseMessage, nil
 }
 
 // ParseJSONParameters decodes specified arguments to JSON objects and replaces the
@@ -100,7 +176,15 @@ func (h *Hook) ExtractCommandArgumentsForEnv(headers, query, payload *map[string
 // Hooks is an array of Hook objects
 type Hooks []Hook
 
-// LoadFromFile attempts to load hooks from specified JSON file
+// LoadFromFile attempts to load hooks from the specified JSON or YAML file.
+// The format is picked by extension (.yaml/.yml is parsed as YAML, anything
+// else as JSON) falling back to the other format if the first attempt fails
+// to parse, so an unrecognised extension still loads correctly. YAML is
+// decoded straight into h via its own `yaml` struct tags, rather than via a
+// generic interface{} round-trip through JSON: yaml.v2 resolves untyped
+// scalars like `on`/`off` and date-like strings to bool/time.Time, and that
+// resolution only happens when the destination is unknown, so decoding
+// directly into the (string-typed) fields keeps their literal text intact.
 func (h *Hooks) LoadFromFile(path string) error {
 	if path == "" {
 		return nil
@@ -113,8 +197,18 @@ func (h *Hooks) LoadFromFile(path string) error {
 		return e
 	}
 
-	e = json.Unmarshal(file, h)
-	return e
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if e = yaml.Unmarshal(file, h); e == nil {
+			return nil
+		}
+		return json.Unmarshal(file, h)
+	default:
+		if e = json.Unmarshal(file, h); e == nil {
+			return nil
+		}
+		return yaml.Unmarshal(file, h)
+	}
 }
 
 // Match iterates through Hooks and returns first one that matches the given ID,
@@ -151,4 +245,5 @@ type CommandStatusResponse struct {
 	ResponseMessage string `json:"message,omitempty"`
 	Output          string `json:"output,omitempty"`
 	Error           string `json:"error,omitempty"`
+	ExitCode        int    `json:"exit-code,omitempty"`
 }
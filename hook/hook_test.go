@@ -0,0 +1,125 @@
+package hook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "hook-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %s", err)
+	}
+
+	return path
+}
+
+func TestLoadFromFileYAMLMatchesJSON(t *testing.T) {
+	jsonHooks := `
+[
+  {
+    "id": "test-hook",
+    "execute-command": "/bin/true",
+    "response-message": "ok",
+    "trigger-rule": {
+      "and": [
+        {
+          "match": {
+            "type": "value",
+            "value": "refs/heads/main",
+            "parameter": {
+              "source": "payload",
+              "name": "ref"
+            }
+          }
+        },
+        {
+          "match": {
+            "type": "payload-hash-sha1",
+            "secret": "mysecret",
+            "parameter": {
+              "source": "header",
+              "name": "X-Hub-Signature"
+            }
+          }
+        }
+      ]
+    }
+  }
+]
+`
+
+	yamlHooks := `
+- id: test-hook
+  execute-command: /bin/true
+  response-message: ok
+  trigger-rule:
+    and:
+      - match:
+          type: value
+          value: refs/heads/main
+          parameter:
+            source: payload
+            name: ref
+      - match:
+          type: payload-hash-sha1
+          secret: mysecret
+          parameter:
+            source: header
+            name: X-Hub-Signature
+`
+
+	var fromJSON, fromYAML Hooks
+
+	jsonPath := writeTempFile(t, "hooks.json", jsonHooks)
+	if err := fromJSON.LoadFromFile(jsonPath); err != nil {
+		t.Fatalf("failed to load JSON hooks: %s", err)
+	}
+
+	yamlPath := writeTempFile(t, "hooks.yaml", yamlHooks)
+	if err := fromYAML.LoadFromFile(yamlPath); err != nil {
+		t.Fatalf("failed to load YAML hooks: %s", err)
+	}
+
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("expected YAML hooks to equal their JSON equivalent\nJSON: %+v\nYAML: %+v", fromJSON, fromYAML)
+	}
+}
+
+func TestLoadFromFileYAMLPreservesAmbiguousScalars(t *testing.T) {
+	yamlHooks := `
+- id: "2024-01-01"
+  execute-command: /bin/true
+  response-message: on
+`
+
+	var hooks Hooks
+
+	path := writeTempFile(t, "hooks.yaml", yamlHooks)
+	if err := hooks.LoadFromFile(path); err != nil {
+		t.Fatalf("failed to load YAML hooks: %s", err)
+	}
+
+	if len(hooks) != 1 {
+		t.Fatalf("expected 1 hook, got %d", len(hooks))
+	}
+
+	if hooks[0].ID != "2024-01-01" {
+		t.Errorf("expected ID %q to stay a literal string, got %q", "2024-01-01", hooks[0].ID)
+	}
+
+	if hooks[0].ResponseMessage != "on" {
+		t.Errorf("expected ResponseMessage %q to stay a literal string, got %q", "on", hooks[0].ResponseMessage)
+	}
+}
@@ -0,0 +1,227 @@
+package hook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractJSONPath evaluates a JSONPath-style expression against params and
+// returns the first matching value. The supported subset covers dotted keys
+// (`commits.0.id`), a leading `$` root marker, wildcard array/object
+// expansion (`[*]`), recursive descent (`..`), and a single-condition
+// equality filter (`[?(@.author.email=="me@x")]`). The existing dotted
+// numeric-index syntax handled by GetParameter keeps working unchanged.
+func ExtractJSONPath(s string, params interface{}) (interface{}, bool) {
+	matches := evaluateJSONPath(splitJSONPath(strings.TrimPrefix(s, "$")), params)
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	return matches[0], true
+}
+
+// ExtractJSONPathAsString extracts a value using ExtractJSONPath and formats
+// it as a string, mirroring ExtractParameterAsString
+func ExtractJSONPathAsString(s string, params interface{}) (string, bool) {
+	if pValue, ok := ExtractJSONPath(s, params); ok {
+		return fmt.Sprintf("%v", pValue), true
+	}
+	return "", false
+}
+
+// splitJSONPath breaks a path expression into segments, keeping bracketed
+// index/wildcard/filter expressions intact and turning a ".." run into its
+// own recursive-descent segment
+func splitJSONPath(expr string) []string {
+	var segments []string
+
+	i, n := 0, len(expr)
+	for i < n {
+		switch {
+		case expr[i] == '.':
+			i++
+			if i < n && expr[i] == '.' {
+				segments = append(segments, "..")
+				i++
+			}
+		case expr[i] == '[':
+			j := i + 1
+			inQuotes := false
+			for j < n && (expr[j] != ']' || inQuotes) {
+				if expr[j] == '"' {
+					inQuotes = !inQuotes
+				}
+				j++
+			}
+			if j < n {
+				j++
+			}
+			segments = append(segments, expr[i:j])
+			i = j
+		default:
+			j := i
+			for j < n && expr[j] != '.' && expr[j] != '[' {
+				j++
+			}
+			if j > i {
+				segments = append(segments, expr[i:j])
+			}
+			i = j
+		}
+	}
+
+	return segments
+}
+
+// evaluateJSONPath walks value according to segments, returning every match.
+// More than one segment can match at once (wildcards, filters, recursive
+// descent), so intermediate results are carried as a slice throughout.
+func evaluateJSONPath(segments []string, value interface{}) []interface{} {
+	if len(segments) == 0 {
+		return []interface{}{value}
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment == ".." {
+		if len(rest) == 0 {
+			return []interface{}{value}
+		}
+
+		var results []interface{}
+		var walk func(v interface{})
+		walk = func(v interface{}) {
+			results = append(results, evaluateJSONPath(rest, v)...)
+
+			switch vv := v.(type) {
+			case map[string]interface{}:
+				for _, child := range vv {
+					walk(child)
+				}
+			case []interface{}:
+				for _, child := range vv {
+					walk(child)
+				}
+			}
+		}
+		walk(value)
+
+		return results
+	}
+
+	var results []interface{}
+	for _, match := range matchJSONPathSegment(segment, value) {
+		results = append(results, evaluateJSONPath(rest, match)...)
+	}
+
+	return results
+}
+
+// matchJSONPathSegment applies a single path segment (a plain key, a numeric
+// index, a `[*]` wildcard, or a `[?(...)]` filter) to value
+func matchJSONPathSegment(segment string, value interface{}) []interface{} {
+	if !strings.HasPrefix(segment, "[") || !strings.HasSuffix(segment, "]") {
+		if m, ok := value.(map[string]interface{}); ok {
+			if v, ok := m[segment]; ok {
+				return []interface{}{v}
+			}
+			return nil
+		}
+
+		// a bare numeric segment (e.g. "commits.0.id") indexes into an
+		// array the same way its bracketed "[0]" form does, keeping the
+		// legacy dotted syntax handled by GetParameter fully supported
+		if items, ok := value.([]interface{}); ok {
+			if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(items) {
+				return []interface{}{items[index]}
+			}
+		}
+
+		return nil
+	}
+
+	inner := segment[1 : len(segment)-1]
+
+	switch {
+	case inner == "*":
+		switch v := value.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			values := make([]interface{}, 0, len(v))
+			for _, cv := range v {
+				values = append(values, cv)
+			}
+			return values
+		}
+		return nil
+
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		var matches []interface{}
+		for _, item := range items {
+			if evaluateJSONPathFilter(inner[2:len(inner)-1], item) {
+				matches = append(matches, item)
+			}
+		}
+		return matches
+
+	default:
+		index, err := strconv.Atoi(inner)
+		if err != nil {
+			return nil
+		}
+
+		if items, ok := value.([]interface{}); ok && index >= 0 && index < len(items) {
+			return []interface{}{items[index]}
+		}
+		return nil
+	}
+}
+
+// evaluateJSONPathFilter evaluates a single `@.path==value` /
+// `@.path!=value` equality condition against item
+func evaluateJSONPathFilter(expr string, item interface{}) bool {
+	op := "=="
+	parts := strings.SplitN(expr, "==", 2)
+	if len(parts) != 2 {
+		op = "!="
+		parts = strings.SplitN(expr, "!=", 2)
+	}
+	if len(parts) != 2 {
+		return false
+	}
+
+	left := resolveJSONPathFilterOperand(strings.TrimSpace(parts[0]), item)
+	right := resolveJSONPathFilterOperand(strings.TrimSpace(parts[1]), item)
+
+	equal := fmt.Sprintf("%v", left) == fmt.Sprintf("%v", right)
+	if op == "!=" {
+		return !equal
+	}
+	return equal
+}
+
+// resolveJSONPathFilterOperand resolves one side of a filter condition:
+// `@.foo.bar` is looked up on item, a quoted string is unquoted, anything
+// else (numbers, booleans) is used verbatim
+func resolveJSONPathFilterOperand(expr string, item interface{}) interface{} {
+	if strings.HasPrefix(expr, "@.") {
+		v, ok := GetParameter(expr[2:], item)
+		if !ok {
+			return nil
+		}
+		return v
+	}
+
+	if len(expr) >= 2 && expr[0] == '"' && expr[len(expr)-1] == '"' {
+		return expr[1 : len(expr)-1]
+	}
+
+	return expr
+}
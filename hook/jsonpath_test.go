@@ -0,0 +1,130 @@
+package hook
+
+import "testing"
+
+func TestExtractJSONPathNestedArray(t *testing.T) {
+	payload := map[string]interface{}{
+		"commits": []interface{}{
+			map[string]interface{}{
+				"id": "abc123",
+				"author": map[string]interface{}{
+					"email": "someone@example.com",
+				},
+			},
+			map[string]interface{}{
+				"id": "def456",
+				"author": map[string]interface{}{
+					"email": "me@x",
+				},
+			},
+		},
+	}
+
+	value, ok := ExtractJSONPath(`$.commits[?(@.author.email=="me@x")].id`, payload)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if value != "def456" {
+		t.Errorf("expected id %q, got %v", "def456", value)
+	}
+}
+
+func TestExtractJSONPathDottedNumericIndex(t *testing.T) {
+	payload := map[string]interface{}{
+		"commits": []interface{}{
+			map[string]interface{}{"id": "abc123"},
+			map[string]interface{}{"id": "def456"},
+		},
+	}
+
+	dotted, ok := ExtractJSONPath("$.commits.0.id", payload)
+	if !ok {
+		t.Fatalf("expected a match for the dotted numeric-index syntax")
+	}
+
+	bracketed, ok := ExtractJSONPath("$.commits[0].id", payload)
+	if !ok {
+		t.Fatalf("expected a match for the bracketed index syntax")
+	}
+
+	if dotted != bracketed {
+		t.Errorf("expected dotted and bracketed indexing to agree, got %v and %v", dotted, bracketed)
+	}
+	if dotted != "abc123" {
+		t.Errorf("expected id %q, got %v", "abc123", dotted)
+	}
+}
+
+func TestExtractJSONPathWildcard(t *testing.T) {
+	payload := map[string]interface{}{
+		"labels": []interface{}{
+			map[string]interface{}{"name": "bug"},
+			map[string]interface{}{"name": "help wanted"},
+		},
+	}
+
+	value, ok := ExtractJSONPath("$.labels[*].name", payload)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if value != "bug" {
+		t.Errorf("expected the first match %q, got %v", "bug", value)
+	}
+}
+
+func TestExtractJSONPathRecursiveDescent(t *testing.T) {
+	payload := map[string]interface{}{
+		"pusher": map[string]interface{}{
+			"name": "alice",
+		},
+		"commits": []interface{}{
+			map[string]interface{}{
+				"author": map[string]interface{}{
+					"name": "bob",
+				},
+			},
+		},
+	}
+
+	value, ok := ExtractJSONPath("$..name", payload)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if value != "alice" && value != "bob" {
+		t.Errorf("expected one of the nested \"name\" values, got %v", value)
+	}
+}
+
+func TestExtractJSONPathMissingKey(t *testing.T) {
+	payload := map[string]interface{}{
+		"commits": []interface{}{
+			map[string]interface{}{"id": "abc123"},
+		},
+	}
+
+	if _, ok := ExtractJSONPath("$.commits[0].author.email", payload); ok {
+		t.Errorf("expected no match for a missing key")
+	}
+
+	if _, ok := ExtractJSONPath("$.does.not.exist", payload); ok {
+		t.Errorf("expected no match for a missing top-level path")
+	}
+}
+
+func TestExtractJSONPathAsString(t *testing.T) {
+	payload := map[string]interface{}{
+		"ref": "refs/heads/main",
+	}
+
+	value, ok := ExtractJSONPathAsString("$.ref", payload)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if value != "refs/heads/main" {
+		t.Errorf("expected %q, got %q", "refs/heads/main", value)
+	}
+
+	if _, ok := ExtractJSONPathAsString("$.missing", payload); ok {
+		t.Errorf("expected no match for a missing key")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"reflect"
 	"strconv"
@@ -20,6 +22,7 @@ const (
 	SourceEntirePayload string = "entire-payload"
 	SourceEntireQuery   string = "entire-query"
 	SourceEntireHeaders string = "entire-headers"
+	SourceJSONPath      string = "jsonpath"
 )
 
 const (
@@ -40,6 +43,18 @@ func (e *SignatureError) Error() string {
 	return fmt.Sprintf("invalid payload signature %s", e.Signature)
 }
 
+// IPAddressError describes an invalid or unparseable remote IP address passed to Hook.
+type IPAddressError struct {
+	Address string
+}
+
+func (e *IPAddressError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("couldn't parse IP address %s", e.Address)
+}
+
 // ArgumentError describes an invalid argument passed to Hook.
 type ArgumentError struct {
 	Argument Argument
@@ -95,6 +110,44 @@ func CheckPayloadSignature(payload []byte, secret string, signature string) (str
 	return expectedMAC, err
 }
 
+// CheckPayloadSignature256 calculates and verifies SHA256 signature of the given payload
+func CheckPayloadSignature256(payload []byte, secret string, signature string) (string, error) {
+	if strings.HasPrefix(signature, "sha256=") {
+		signature = signature[7:]
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, err := mac.Write(payload)
+	if err != nil {
+		return "", err
+	}
+	expectedMAC := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expectedMAC)) {
+		return expectedMAC, &SignatureError{expectedMAC}
+	}
+	return expectedMAC, err
+}
+
+// CheckPayloadSignature512 calculates and verifies SHA512 signature of the given payload
+func CheckPayloadSignature512(payload []byte, secret string, signature string) (string, error) {
+	if strings.HasPrefix(signature, "sha512=") {
+		signature = signature[7:]
+	}
+
+	mac := hmac.New(sha512.New, []byte(secret))
+	_, err := mac.Write(payload)
+	if err != nil {
+		return "", err
+	}
+	expectedMAC := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expectedMAC)) {
+		return expectedMAC, &SignatureError{expectedMAC}
+	}
+	return expectedMAC, err
+}
+
 // ReplaceParameter replaces parameter value with the passed value in the passed map
 // (please note you should pass pointer to the map, because we're modifying it)
 // based on the passed string
@@ -193,8 +246,8 @@ func ExtractParameterAsString(s string, params interface{}) (string, bool) {
 // Argument type specifies the parameter key name and the source it should
 // be extracted from
 type Argument struct {
-	Source string `json:"source,omitempty"`
-	Name   string `json:"name,omitempty"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
 }
 
 // Get Argument method returns the value for the Argument's key name
@@ -235,6 +288,12 @@ func (ha *Argument) Get(headers, query, payload *map[string]interface{}) (string
 		}
 
 		return string(r), true
+	case SourceJSONPath:
+		if payload == nil {
+			return "", false
+		}
+
+		return ExtractJSONPathAsString(ha.Name, *payload)
 	}
 
 	if source != nil {
@@ -1,27 +1,49 @@
 package hook
 
-import "regexp"
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// EvaluateContext carries everything a rule might need to evaluate itself
+// against an incoming request. Bundling these together, instead of passing
+// each one as a separate parameter, lets new rule types depend on additional
+// request data without changing the signature of Evaluate again.
+type EvaluateContext struct {
+	Headers    *map[string]interface{}
+	Query      *map[string]interface{}
+	Payload    *map[string]interface{}
+	Body       *[]byte
+	RemoteAddr string
+}
 
 // Rules is a structure that contains one of the valid rule types
 type Rules struct {
-	And   *AndRule   `json:"and,omitempty"`
-	Or    *OrRule    `json:"or,omitempty"`
-	Not   *NotRule   `json:"not,omitempty"`
-	Match *MatchRule `json:"match,omitempty"`
+	And   *AndRule   `json:"and,omitempty" yaml:"and,omitempty"`
+	Or    *OrRule    `json:"or,omitempty" yaml:"or,omitempty"`
+	Not   *NotRule   `json:"not,omitempty" yaml:"not,omitempty"`
+	Match *MatchRule `json:"match,omitempty" yaml:"match,omitempty"`
+	IP    *IPRule    `json:"ip-whitelist,omitempty" yaml:"ip-whitelist,omitempty"`
+	Expr  *ExprRule  `json:"expr,omitempty" yaml:"expr,omitempty"`
 }
 
 // Evaluate finds the first rule property that is not nil and returns the value
 // it evaluates to
-func (r Rules) Evaluate(headers, query, payload *map[string]interface{}, body *[]byte) (bool, error) {
+func (r Rules) Evaluate(ctx *EvaluateContext) (bool, error) {
 	switch {
 	case r.And != nil:
-		return r.And.Evaluate(headers, query, payload, body)
+		return r.And.Evaluate(ctx)
 	case r.Or != nil:
-		return r.Or.Evaluate(headers, query, payload, body)
+		return r.Or.Evaluate(ctx)
 	case r.Not != nil:
-		return r.Not.Evaluate(headers, query, payload, body)
+		return r.Not.Evaluate(ctx)
 	case r.Match != nil:
-		return r.Match.Evaluate(headers, query, payload, body)
+		return r.Match.Evaluate(ctx)
+	case r.IP != nil:
+		return r.IP.Evaluate(ctx)
+	case r.Expr != nil:
+		return r.Expr.Evaluate(ctx)
 	}
 
 	return false, nil
@@ -31,11 +53,11 @@ func (r Rules) Evaluate(headers, query, payload *map[string]interface{}, body *[
 type AndRule []Rules
 
 // Evaluate AndRule will return true if and only if all of ChildRules evaluate to true
-func (r AndRule) Evaluate(headers, query, payload *map[string]interface{}, body *[]byte) (bool, error) {
+func (r AndRule) Evaluate(ctx *EvaluateContext) (bool, error) {
 	res := true
 
 	for _, v := range r {
-		rv, err := v.Evaluate(headers, query, payload, body)
+		rv, err := v.Evaluate(ctx)
 		if err != nil {
 			return false, err
 		}
@@ -53,11 +75,11 @@ func (r AndRule) Evaluate(headers, query, payload *map[string]interface{}, body
 type OrRule []Rules
 
 // Evaluate OrRule will return true if any of ChildRules evaluate to true
-func (r OrRule) Evaluate(headers, query, payload *map[string]interface{}, body *[]byte) (bool, error) {
+func (r OrRule) Evaluate(ctx *EvaluateContext) (bool, error) {
 	res := false
 
 	for _, v := range r {
-		rv, err := v.Evaluate(headers, query, payload, body)
+		rv, err := v.Evaluate(ctx)
 		if err != nil {
 			return false, err
 		}
@@ -75,41 +97,107 @@ func (r OrRule) Evaluate(headers, query, payload *map[string]interface{}, body *
 type NotRule Rules
 
 // Evaluate NotRule will return true if and only if ChildRule evaluates to false
-func (r NotRule) Evaluate(headers, query, payload *map[string]interface{}, body *[]byte) (bool, error) {
-	rv, err := Rules(r).Evaluate(headers, query, payload, body)
+func (r NotRule) Evaluate(ctx *EvaluateContext) (bool, error) {
+	rv, err := Rules(r).Evaluate(ctx)
 	return !rv, err
 }
 
 // MatchRule will evaluate to true based on the type
 type MatchRule struct {
-	Type      string   `json:"type,omitempty"`
-	Regex     string   `json:"regex,omitempty"`
-	Secret    string   `json:"secret,omitempty"`
-	Value     string   `json:"value,omitempty"`
-	Parameter Argument `json:"parameter,omitempty"`
+	Type      string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Regex     string   `json:"regex,omitempty" yaml:"regex,omitempty"`
+	Secret    string   `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Value     string   `json:"value,omitempty" yaml:"value,omitempty"`
+	Parameter Argument `json:"parameter,omitempty" yaml:"parameter,omitempty"`
 }
 
 // Constants for the MatchRule type
 const (
-	MatchValue    string = "value"
-	MatchRegex    string = "regex"
-	MatchHashSHA1 string = "payload-hash-sha1"
+	MatchValue      string = "value"
+	MatchRegex      string = "regex"
+	MatchHashSHA1   string = "payload-hash-sha1"
+	MatchHashSHA256 string = "payload-hash-sha256"
+	MatchHashSHA512 string = "payload-hash-sha512"
 )
 
 // Evaluate MatchRule will return based on the type
-func (r MatchRule) Evaluate(headers, query, payload *map[string]interface{}, body *[]byte) (bool, error) {
-	if arg, ok := r.Parameter.Get(headers, query, payload); ok {
+func (r MatchRule) Evaluate(ctx *EvaluateContext) (bool, error) {
+	if arg, ok := r.Parameter.Get(ctx.Headers, ctx.Query, ctx.Payload); ok {
 		switch r.Type {
 		case MatchValue:
 			return arg == r.Value, nil
 		case MatchRegex:
 			return regexp.MatchString(r.Regex, arg)
 		case MatchHashSHA1:
-			_, err := CheckPayloadSignature(*body, r.Secret, arg)
+			_, err := CheckPayloadSignature(*ctx.Body, r.Secret, arg)
+			return err == nil, err
+		case MatchHashSHA256:
+			_, err := CheckPayloadSignature256(*ctx.Body, r.Secret, arg)
 			return err == nil, err
+		case MatchHashSHA512:
+			_, err := CheckPayloadSignature512(*ctx.Body, r.Secret, arg)
+			return err == nil, err
+		}
+	}
+	return false, nil
+}
+
+// IPRule will evaluate to true if the request's remote address matches one of
+// the comma-separated CIDRs in IPRange
+type IPRule struct {
+	IPRange string `json:"ip-range,omitempty" yaml:"ip-range,omitempty"`
+}
+
+// Evaluate IPRule will return true if the client IP falls within any of the
+// CIDRs in IPRange. The client IP is taken from the first hop of the
+// X-Forwarded-For header when present (the common case behind a load
+// balancer or reverse proxy), falling back to ctx.RemoteAddr otherwise.
+func (r IPRule) Evaluate(ctx *EvaluateContext) (bool, error) {
+	host := clientIP(ctx)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false, &IPAddressError{host}
+	}
+
+	for _, cidr := range strings.Split(r.IPRange, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return false, err
+		}
+
+		if ipnet.Contains(ip) {
+			return true, nil
 		}
 	}
+
 	return false, nil
 }
 
+// clientIP returns the first hop of ctx.Headers' X-Forwarded-For header, if
+// present, or ctx.RemoteAddr otherwise
+func clientIP(ctx *EvaluateContext) string {
+	if ctx.Headers != nil {
+		for name, value := range *ctx.Headers {
+			if !strings.EqualFold(name, "X-Forwarded-For") {
+				continue
+			}
+
+			if forwarded, ok := value.(string); ok {
+				if first := strings.TrimSpace(strings.Split(forwarded, ",")[0]); first != "" {
+					return first
+				}
+			}
+		}
+	}
 
+	return ctx.RemoteAddr
+}
@@ -0,0 +1,167 @@
+package hook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long HooksWatcher waits after the last filesystem
+// event before reloading, so that editors which emit several events per save
+// (write, then rename-into-place, ...) only trigger a single reload.
+const watchDebounce = 200 * time.Millisecond
+
+// HooksWatcher keeps an in-memory Hooks value in sync with a hook definition
+// file, or a directory of them, reloading and atomically swapping it in
+// whenever the underlying file(s) change on disk.
+type HooksWatcher struct {
+	path string
+
+	mu    sync.RWMutex
+	hooks Hooks
+}
+
+// NewHooksWatcher loads the hooks at path (a single file, or a directory
+// containing *.json/*.yaml/*.yml hook files, all of which are merged
+// together) and returns a HooksWatcher ready to be started with Watch.
+func NewHooksWatcher(path string) (*HooksWatcher, error) {
+	hooks, err := loadHooksFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HooksWatcher{path: path, hooks: hooks}, nil
+}
+
+// Hooks returns the current snapshot of loaded hooks. It is safe to call
+// concurrently with Watch; requests already in flight keep using the
+// snapshot they read and are never handed a partially reloaded value.
+func (w *HooksWatcher) Hooks() Hooks {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.hooks
+}
+
+// Watch observes w's underlying file or directory for writes, creates and
+// renames, debounces bursts of events, and reloads and validates the new
+// hooks before atomically swapping them in. onChange, if non-nil, is called
+// after every reload attempt with the resulting Hooks (unchanged on error)
+// and any error encountered. Watch blocks until ctx is cancelled.
+func (w *HooksWatcher) Watch(ctx context.Context, onChange func(Hooks, error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := addWatchTargets(watcher, w.path); err != nil {
+		return err
+	}
+
+	var timer *time.Timer
+	reload := func() {
+		hooks, err := loadHooksFromPath(w.path)
+		if err == nil {
+			w.mu.Lock()
+			w.hooks = hooks
+			w.mu.Unlock()
+		}
+		if onChange != nil {
+			// w.Hooks() rather than the local hooks/err result: on failure
+			// hooks is always nil, but the watcher keeps serving the last
+			// good snapshot, and callers should see that, not zero hooks.
+			onChange(w.Hooks(), err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(watchDebounce, reload)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			if onChange != nil {
+				onChange(w.Hooks(), err)
+			}
+		}
+	}
+}
+
+// addWatchTargets registers path with watcher, watching its parent
+// directory when path is a single file so that renames-into-place (the
+// pattern most editors use to save) are still observed
+func addWatchTargets(watcher *fsnotify.Watcher, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return watcher.Add(path)
+	}
+
+	return watcher.Add(filepath.Dir(path))
+}
+
+// loadHooksFromPath loads a single hook definitions file, or merges every
+// *.json/*.yaml/*.yml file directly inside path into one Hooks value when
+// path is a directory
+func loadHooksFromPath(path string) (Hooks, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		var hooks Hooks
+		if err := hooks.LoadFromFile(path); err != nil {
+			return nil, err
+		}
+		return hooks, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged Hooks
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		var hooks Hooks
+		if err := hooks.LoadFromFile(filepath.Join(path, entry.Name())); err != nil {
+			return nil, fmt.Errorf("%s: %s", entry.Name(), err)
+		}
+		merged = append(merged, hooks...)
+	}
+
+	return merged, nil
+}